@@ -1,11 +1,17 @@
 package di
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"runtime"
 	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // TODO:
@@ -86,80 +92,661 @@ import (
 //			MyService *MyService
 //		}
 //		provider.Provide(&Deps{})
+//
+// Multiple providers may produce the same Go type as long as they are
+// distinguished by name, see Named and Uses.
 type Provider struct {
-	allProvidedTypes map[reflect.Type]providerInfo
-	resolvedTypes    map[reflect.Type]reflect.Value
+	allProvidedTypes map[depKey]providerInfo
+	resolvedTypes    map[depKey]reflect.Value
+	aliases          map[depKey]depKey
+	modules          map[string]map[depKey]struct{}
+	// groups maps a Group name, scoped to the module it was registered in,
+	// to the depKey of every provider registered under it, in registration
+	// order, for di:"group=name" slice fields. Scoping by module mirrors
+	// depKey.scope: a group registered inside a module is private to it,
+	// just like any other unexported provider, so two modules (or a module
+	// and the top level) can reuse the same group name without colliding.
+	groups map[groupKey][]depKey
+	// topoOrder lists every canonical depKey such that a provider's
+	// dependencies always appear before it. It is computed once, up front,
+	// so Provide can construct values iteratively instead of recursing.
+	topoOrder []depKey
+	// topoIndex is topoOrder's inverse, so a depKey's position in topoOrder
+	// can be looked up in O(1) when sorting lifecycle hooks.
+	topoIndex map[depKey]int
+	// hooks collects every lifecycle hook registered by a provider (either
+	// explicitly via a Lifecycle parameter, or auto-detected from a
+	// constructed value), tagged with the depKey of the provider that
+	// registered it so Start/Stop can order them.
+	hooks []registeredHook
 }
 
 func NewProvider(depProviders ...any) (*Provider, error) {
-	allProvidedTypes, err := parseProviders(depProviders...)
+	allProvidedTypes, aliases, moduleExports, groups, err := parseProviders(depProviders...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse providers: %w", err)
 	}
 
-	if err := allSubDepsProvided(allProvidedTypes); err != nil {
+	if err := allSubDepsProvided(allProvidedTypes, aliases); err != nil {
 		return nil, fmt.Errorf("all deps must be provided: %w", err)
 	}
 
-	if err := checkForCyclicDependencies(allProvidedTypes); err != nil {
+	graph := dependencyGraph(allProvidedTypes, aliases)
+
+	if err := checkForCyclicDependencies(allProvidedTypes, graph); err != nil {
 		return nil, fmt.Errorf("should not have cyclic dependencies: %w", err)
 	}
 
+	topoOrder := topologicalOrder(allProvidedTypes, graph)
+	topoIndex := make(map[depKey]int, len(topoOrder))
+	for i, key := range topoOrder {
+		topoIndex[key] = i
+	}
+
 	return &Provider{
 		allProvidedTypes: allProvidedTypes,
-		resolvedTypes:    make(map[reflect.Type]reflect.Value),
+		resolvedTypes:    make(map[depKey]reflect.Value),
+		aliases:          aliases,
+		modules:          moduleExports,
+		groups:           groups,
+		topoOrder:        topoOrder,
+		topoIndex:        topoIndex,
 	}, nil
 }
 
+// depKey identifies a provided value by its Go type and, optionally, a name
+// that distinguishes it from other providers of the same type (e.g. two
+// *sql.DB instances for a primary and a replica). An empty name refers to
+// the unnamed/default slot for the type.
+//
+// scope namespaces a type to the module it was privately provided in, so
+// that two modules can each provide the same type without colliding. The
+// top-level dependency graph, and anything a module exports, lives in the
+// empty scope.
+type depKey struct {
+	typ   reflect.Type
+	name  string
+	scope string
+}
+
+// groupKey identifies a Group by name within the module scope it was
+// registered in, the same way depKey.scope namespaces a type to its module.
+// An empty scope is the top-level group namespace.
+type groupKey struct {
+	scope string
+	name  string
+}
+
+func (k depKey) String() string {
+	s := k.typ.String()
+	if k.name != "" {
+		s = fmt.Sprintf("%s (name=%s)", s, k.name)
+	}
+	if k.scope != "" {
+		s = fmt.Sprintf("%s (module=%s)", s, k.scope)
+	}
+	return s
+}
+
+// Module groups a set of provider functions (and optionally other modules)
+// under a name. Providers inside a module are private to it unless named in
+// Export, so two modules can privately provide the same type without
+// colliding, e.g. an "auth" module and a "billing" module can each have
+// their own *Config type. A module can also depend on another module's
+// exports via Use. Example usage:
+//
+//	authModule := di.NewModule("auth",
+//		func() *Config { return &Config{...} },
+//		func(cfg *Config) *TokenIssuer { return &TokenIssuer{cfg} },
+//	).Export((*TokenIssuer)(nil))
+//
+//	billingModule := di.NewModule("billing",
+//		func() *Config { return &Config{...} },
+//		func(issuer *TokenIssuer) *Billing { return &Billing{issuer} },
+//	).Use(authModule).Export((*Billing)(nil))
+//
+//	provider, err := di.NewProvider(authModule, billingModule)
+type Module struct {
+	name      string
+	providers []any
+	uses      []*Module
+	exports   map[reflect.Type]struct{}
+}
+
+// NewModule creates a Module named name from the given providers. A
+// provider may itself be a *Module, in which case it is nested under this
+// module rather than being globally visible.
+func NewModule(name string, providers ...any) *Module {
+	return &Module{name: name, providers: providers}
+}
+
+// Export marks which types this module provides are visible outside of it.
+// Pass a representative value for each type, e.g. (*DB)(nil) or DB{}.
+func (m *Module) Export(types ...any) *Module {
+	if m.exports == nil {
+		m.exports = make(map[reflect.Type]struct{}, len(types))
+	}
+	for _, t := range types {
+		m.exports[reflect.TypeOf(t)] = struct{}{}
+	}
+	return m
+}
+
+// Use imports another module's exports into this module's dependency scope,
+// so this module's own providers can depend on them.
+func (m *Module) Use(other *Module) *Module {
+	m.uses = append(m.uses, other)
+	return m
+}
+
+// Hook is a pair of callbacks a provider registers with a Lifecycle so the
+// container can start and stop it alongside the rest of the application,
+// e.g. opening and closing a connection pool or starting and stopping a
+// background worker.
+type Hook interface {
+	OnStart(ctx context.Context) error
+	OnStop(ctx context.Context) error
+}
+
+// Lifecycle lets a provider register Hooks to be run by Provider.Start and
+// Provider.Stop. A provider declares it needs one simply by taking a
+// Lifecycle parameter; the container supplies its own implementation, so
+// Lifecycle is never looked up in allProvidedTypes like a normal dependency.
+type Lifecycle interface {
+	Append(hook Hook)
+}
+
+// HookTimeout is an optional Hook extension: a Hook that also implements it
+// caps how long a single OnStart or OnStop call may run. Start and Stop
+// apply the returned duration via context.WithTimeout around that call, so
+// a well-behaved hook that respects ctx cancellation can't block shutdown
+// (or startup) forever. A Hook that doesn't implement HookTimeout, or
+// returns a non-positive duration, runs under the caller's ctx unmodified.
+type HookTimeout interface {
+	Timeout() time.Duration
+}
+
+// lifecycleType is compared against a provider's input types to recognize a
+// Lifecycle parameter during parsing.
+var lifecycleType = reflect.TypeOf((*Lifecycle)(nil)).Elem()
+
+// registeredHook is a Hook together with the depKey of the provider that
+// registered it, so Start/Stop can order hooks by that provider's position
+// in topoOrder.
+type registeredHook struct {
+	key  depKey
+	hook Hook
+}
+
+// lifecycleRecorder is the concrete Lifecycle the container passes to a
+// provider that asks for one. key identifies the provider being
+// constructed, so hooks it registers can later be ordered correctly.
+type lifecycleRecorder struct {
+	provider *Provider
+	key      depKey
+}
+
+func (r *lifecycleRecorder) Append(hook Hook) {
+	r.provider.hooks = append(r.provider.hooks, registeredHook{key: r.key, hook: hook})
+}
+
+// starterStopper is the implicit lifecycle shape resolve auto-detects on a
+// freshly constructed value, alongside io.Closer.
+type starterStopper interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type starterStopperHook struct{ v starterStopper }
+
+func (h starterStopperHook) OnStart(ctx context.Context) error { return h.v.Start(ctx) }
+func (h starterStopperHook) OnStop(ctx context.Context) error  { return h.v.Stop(ctx) }
+
+type closerHook struct{ v io.Closer }
+
+func (closerHook) OnStart(context.Context) error  { return nil }
+func (h closerHook) OnStop(context.Context) error { return h.v.Close() }
+
+// namedProvider wraps a provider function with an optional name under which
+// its output is registered, and optional names for its input positions.
+// Users build it via Named and Uses rather than constructing it directly.
+type namedProvider struct {
+	fn       any
+	name     string
+	usesName map[int]string
+}
+
+// Named annotates a provider function so the value it produces is
+// registered under name instead of the unnamed/default slot for its type.
+// It can be combined with Uses on the same provider.
+func Named(name string, fn any) any {
+	np := asNamedProvider(fn)
+	np.name = name
+	return np
+}
+
+// Uses annotates a provider function so that its dependency at the given
+// input position is resolved from the named instance name instead of the
+// unnamed one. It can be combined with Named on the same provider.
+func Uses(position int, name string, fn any) any {
+	np := asNamedProvider(fn)
+	if np.usesName == nil {
+		np.usesName = make(map[int]string)
+	}
+	np.usesName[position] = name
+	return np
+}
+
+func asNamedProvider(fn any) *namedProvider {
+	if np, ok := fn.(*namedProvider); ok {
+		return np
+	}
+	return &namedProvider{fn: fn}
+}
+
+// bindProvider wraps a provider function so its output is also resolvable
+// as iface. Users build it via Bind rather than constructing it directly.
+type bindProvider struct {
+	fn    any
+	iface reflect.Type
+}
+
+// Bind registers fn like a normal provider, but also binds its output type
+// to Iface, so dependents can ask for Iface instead of the concrete type
+// fn returns. Useful when a concrete implementation should satisfy an
+// interface-typed dependency, e.g.:
+//
+//	di.Bind[Storage](func() *S3Storage { return &S3Storage{} })
+func Bind[Iface any](fn any) any {
+	return &bindProvider{fn: fn, iface: reflect.TypeOf((*Iface)(nil)).Elem()}
+}
+
+// groupProvider wraps a provider function so its output is collected into
+// a named group instead of claiming the unnamed/default slot for its type.
+// Users build it via Group rather than constructing it directly.
+type groupProvider struct {
+	fn    any
+	group string
+}
+
+// Group annotates a provider function so its output is added to the named
+// group rather than registered as the sole provider of its type. Multiple
+// providers, even of the same type, may contribute to the same group
+// without triggering the duplicate-type error. Collect the group with a
+// `di:"group=name"` tag on a slice field whose element type each group
+// member is assignable to, e.g.:
+//
+//	di.Group("handlers", func() Handler { return &PingHandler{} })
+//	di.Group("handlers", func() Handler { return &HealthHandler{} })
+//
+//	dst := &struct {
+//		Handlers []Handler `di:"group=handlers"`
+//	}{}
+func Group(name string, fn any) any {
+	return &groupProvider{fn: fn, group: name}
+}
+
+// Optional wraps a provider function's input parameter to declare that the
+// dependency need not be registered. If it isn't, the provider still runs,
+// receiving an Optional with Ok false and Value left at T's zero value.
+//
+//	func NewTracer(exporter di.Optional[*Exporter]) *Tracer {
+//		if e, ok := exporter.Get(); ok {
+//			return &Tracer{Exporter: e}
+//		}
+//		return &Tracer{} // no-op tracer
+//	}
+type Optional[T any] struct {
+	Value T
+	Ok    bool
+}
+
+// Get returns the dependency and whether it was actually registered.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Ok
+}
+
+// optionalType reports the type an Optional[T] wraps, letting
+// parseOneProvider recognize an Optional[T] input parameter and recover T
+// without knowing it ahead of time.
+func (Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// optionalMarker is implemented by every Optional[T] instantiation.
+type optionalMarker interface {
+	optionalType() reflect.Type
+}
+
+// optionalValue builds the Optional[T] value to pass into an optional
+// parameter at optionalType (the provider function's declared Optional[T]
+// input type): Ok and, if present, Value are set by reflection since
+// Optional's fields are exported for exactly this purpose.
+func optionalValue(optionalType reflect.Type, depValue reflect.Value, present bool) reflect.Value {
+	wrapper := reflect.New(optionalType).Elem()
+	wrapper.FieldByName("Ok").SetBool(present)
+	if present {
+		wrapper.FieldByName("Value").Set(depValue)
+	}
+	return wrapper
+}
+
 type providerInfo struct {
 	providerName string
-	providedType reflect.Type
-	deps         []reflect.Type
+	providedType depKey
+	deps         []depKey
+	// lifecycleParam is the input position of a Lifecycle parameter, or -1
+	// if the provider doesn't take one. The corresponding entry in deps is
+	// a zero-value placeholder, kept only so deps stays aligned with the
+	// provider function's input positions for Call.
+	lifecycleParam int
+	// optionalDeps marks which positions in deps came from an Optional[T]
+	// parameter, so they don't have to be provided and are passed as an
+	// Optional value rather than T itself.
+	optionalDeps map[int]bool
 	provider     reflect.Value // function
 }
 
-func parseProviders(depProviders ...any) (map[reflect.Type]providerInfo, error) {
-	parsed := make(map[reflect.Type]providerInfo, len(depProviders))
+// parseProviders builds the top-level dependency graph from a mix of plain
+// provider functions, *Module values, and Bind/Group wrappers. It returns
+// the flat map of canonical providers, an alias table mapping an importable
+// depKey to the canonical depKey that actually produces it (used for both
+// module exports and Bind's interface bindings), for each named top-level
+// module the set of depKeys it exports (so Provider.ProvideModule can scope
+// injection to it), and a table of every Group's member depKeys.
+func parseProviders(depProviders ...any) (map[depKey]providerInfo, map[depKey]depKey, map[string]map[depKey]struct{}, map[groupKey][]depKey, error) {
+	parsed := make(map[depKey]providerInfo, len(depProviders))
+	aliases := make(map[depKey]depKey)
+	moduleScopes := make(map[*Module]string)
+	modules := make(map[string]map[depKey]struct{})
+	groups := make(map[groupKey][]depKey)
 	for i, provider := range depProviders {
-		providerType := reflect.TypeOf(provider)
-		if providerType.Kind() != reflect.Func {
-			return nil, fmt.Errorf("%dth provider is not a function, got %s", i, providerType.Kind())
+		if m, ok := provider.(*Module); ok {
+			exports, err := parseModule(m, "", parsed, aliases, moduleScopes, groups)
+			if err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("%dth provider: %w", i, err)
+			}
+			if _, ok := modules[m.name]; ok {
+				return nil, nil, nil, nil, fmt.Errorf("%dth provider: module %q is provided more than once", i, m.name)
+			}
+			if err := registerAliases(m.name, exports, parsed, aliases); err != nil {
+				return nil, nil, nil, nil, err
+			}
+			exportedKeys := make(map[depKey]struct{}, len(exports))
+			for alias := range exports {
+				exportedKeys[alias] = struct{}{}
+			}
+			modules[m.name] = exportedKeys
+			continue
 		}
-		providerName, err := getFunctionName(provider)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get function name for provider %d: %w", i, err)
+
+		if handled, err := parseBoundOrGroupedProvider(i, provider, "", parsed, aliases, groups); handled {
+			if err != nil {
+				return nil, nil, nil, nil, err
+			}
+			continue
 		}
-		outCount := providerType.NumOut()
-		if outCount < 1 {
-			return nil, fmt.Errorf("%dth provider %s has no output", i, providerName)
+
+		if _, err := parseOneProvider(i, provider, "", parsed); err != nil {
+			return nil, nil, nil, nil, err
 		}
-		if outCount > 2 {
-			return nil, fmt.Errorf("%dth provider %s has more than two outputs. Provider must return a single value or a value and an error", i, providerName)
+	}
+
+	return parsed, aliases, modules, groups, nil
+}
+
+// parseModule flattens a module's own providers into the shared parsed
+// table, scoped to the module's path (parentScope + "." + m.name) so two
+// modules can privately provide the same type without colliding, and
+// returns the subset of that namespace the module chose to Export: a map
+// from the depKey its caller (another module, or the top-level graph) will
+// ask for, to the canonical depKey that actually holds the provider.
+//
+// Each *Module is only ever parsed once, keyed by pointer identity in
+// scopes, so the same module reused via Use (or passed directly alongside a
+// module that uses it) still resolves to a single shared instance of its
+// providers rather than constructing them twice.
+func parseModule(m *Module, parentScope string, parsed map[depKey]providerInfo, aliases map[depKey]depKey, scopes map[*Module]string, groups map[groupKey][]depKey) (map[depKey]depKey, error) {
+	scope, alreadyParsed := scopes[m]
+	if !alreadyParsed {
+		scope = m.name
+		if parentScope != "" {
+			scope = parentScope + "." + m.name
 		}
-		if outCount == 2 {
-			if providerType.Out(1).Kind() != reflect.Interface || providerType.Out(1).String() != "error" {
-				return nil, fmt.Errorf("%dth provider %s has two outputs, but the second one is not an error", i, providerName)
+		scopes[m] = scope
+
+		for _, used := range m.uses {
+			usedExports, err := parseModule(used, scope, parsed, aliases, scopes, groups)
+			if err != nil {
+				return nil, err
+			}
+			if err := registerAliases(m.name, usedExports, parsed, aliases); err != nil {
+				return nil, err
 			}
 		}
-		out := providerType.Out(0)
-		if duplicateProvider, ok := parsed[out]; ok {
-			return nil, fmt.Errorf("%dth provider %s returns the same type %s as provider %s", i, providerName, out, duplicateProvider.providerName)
+
+		for i, provider := range m.providers {
+			if sub, ok := provider.(*Module); ok {
+				subExports, err := parseModule(sub, scope, parsed, aliases, scopes, groups)
+				if err != nil {
+					return nil, err
+				}
+				if err := registerAliases(m.name, subExports, parsed, aliases); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if handled, err := parseBoundOrGroupedProvider(i, provider, scope, parsed, aliases, groups); handled {
+				if err != nil {
+					return nil, fmt.Errorf("module %q: %w", m.name, err)
+				}
+				continue
+			}
+
+			if _, err := parseOneProvider(i, provider, scope, parsed); err != nil {
+				return nil, fmt.Errorf("module %q: %w", m.name, err)
+			}
 		}
+	}
 
-		deps := make([]reflect.Type, 0, providerType.NumIn())
-		for j := 0; j < providerType.NumIn(); j++ {
-			deps = append(deps, providerType.In(j))
+	exported := make(map[depKey]depKey, len(m.exports))
+	for t := range m.exports {
+		matches := typesInScope(scope, t, parsed, aliases)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("module %q: cannot export type %s, no provider for it", m.name, t)
+		}
+		for key, canonical := range matches {
+			exported[depKey{typ: key.typ, name: key.name, scope: parentScope}] = canonical
 		}
+	}
+	return exported, nil
+}
 
-		parsed[out] = providerInfo{
-			providerName: providerName,
-			providedType: out,
-			deps:         deps,
-			provider:     reflect.ValueOf(provider),
+// typesInScope collects every depKey of type t visible directly within
+// scope, whether it is one of the scope's own providers or a binding it
+// imported via Use, mapped to the canonical depKey that actually produces
+// the value.
+func typesInScope(scope string, t reflect.Type, parsed map[depKey]providerInfo, aliases map[depKey]depKey) map[depKey]depKey {
+	matches := make(map[depKey]depKey)
+	for key := range parsed {
+		if key.scope == scope && key.typ == t {
+			matches[key] = key
 		}
 	}
+	for key, canonical := range aliases {
+		if key.scope == scope && key.typ == t {
+			matches[key] = canonical
+		}
+	}
+	return matches
+}
 
-	return parsed, nil
+// registerAliases merges a module's exports (or imports) into the shared
+// alias table, reporting a collision in terms of the module that caused it.
+func registerAliases(moduleName string, exports map[depKey]depKey, parsed map[depKey]providerInfo, aliases map[depKey]depKey) error {
+	for alias, canonical := range exports {
+		if duplicate, ok := aliases[alias]; ok {
+			return fmt.Errorf("module %q: provider %s returns the same type %s as provider %s", moduleName, parsed[canonical].providerName, alias, parsed[duplicate].providerName)
+		}
+		if duplicate, ok := parsed[alias]; ok {
+			return fmt.Errorf("module %q: provider %s returns the same type %s as provider %s", moduleName, parsed[canonical].providerName, alias, duplicate.providerName)
+		}
+		aliases[alias] = canonical
+	}
+	return nil
+}
+
+// parseOneProvider validates a single provider function (optionally wrapped
+// in Named/Uses) and, on success, registers it into parsed under the given
+// scope, returning the depKey it was registered under.
+func parseOneProvider(i int, provider any, scope string, parsed map[depKey]providerInfo) (depKey, error) {
+	name := ""
+	usesName := map[int]string(nil)
+	if np, ok := provider.(*namedProvider); ok {
+		name = np.name
+		usesName = np.usesName
+		provider = np.fn
+	}
+
+	providerType := reflect.TypeOf(provider)
+	if providerType.Kind() != reflect.Func {
+		return depKey{}, fmt.Errorf("%dth provider is not a function, got %s", i, providerType.Kind())
+	}
+	providerName, err := getFunctionName(provider)
+	if err != nil {
+		return depKey{}, fmt.Errorf("failed to get function name for provider %d: %w", i, err)
+	}
+	outCount := providerType.NumOut()
+	if outCount < 1 {
+		return depKey{}, fmt.Errorf("%dth provider %s has no output", i, providerName)
+	}
+	if outCount > 2 {
+		return depKey{}, fmt.Errorf("%dth provider %s has more than two outputs. Provider must return a single value or a value and an error", i, providerName)
+	}
+	if outCount == 2 {
+		if providerType.Out(1).Kind() != reflect.Interface || providerType.Out(1).String() != "error" {
+			return depKey{}, fmt.Errorf("%dth provider %s has two outputs, but the second one is not an error", i, providerName)
+		}
+	}
+	out := depKey{typ: providerType.Out(0), name: name, scope: scope}
+	if duplicateProvider, ok := parsed[out]; ok {
+		return depKey{}, fmt.Errorf("%dth provider %s returns the same type %s as provider %s", i, providerName, out, duplicateProvider.providerName)
+	}
+
+	lifecycleParam := -1
+	var optionalDeps map[int]bool
+	deps := make([]depKey, 0, providerType.NumIn())
+	for j := 0; j < providerType.NumIn(); j++ {
+		inType := providerType.In(j)
+		if inType == lifecycleType {
+			lifecycleParam = j
+			deps = append(deps, depKey{})
+			continue
+		}
+		if zero, ok := reflect.New(inType).Elem().Interface().(optionalMarker); ok {
+			if optionalDeps == nil {
+				optionalDeps = make(map[int]bool)
+			}
+			optionalDeps[j] = true
+			deps = append(deps, depKey{typ: zero.optionalType(), name: usesName[j], scope: scope})
+			continue
+		}
+		deps = append(deps, depKey{typ: inType, name: usesName[j], scope: scope})
+	}
+
+	parsed[out] = providerInfo{
+		providerName:   providerName,
+		providedType:   out,
+		deps:           deps,
+		lifecycleParam: lifecycleParam,
+		optionalDeps:   optionalDeps,
+		provider:       reflect.ValueOf(provider),
+	}
+	return out, nil
+}
+
+// unwrapNamed reports the name/usesName annotations a *namedProvider carries
+// and the fn underneath it, or a zero name/usesName and provider itself
+// unchanged if it isn't a *namedProvider.
+func unwrapNamed(provider any) (name string, usesName map[int]string, fn any) {
+	if np, ok := provider.(*namedProvider); ok {
+		return np.name, np.usesName, np.fn
+	}
+	return "", nil, provider
+}
+
+// wrapNamed is the inverse of unwrapNamed: it re-wraps fn in a fresh
+// *namedProvider carrying name/usesName, or returns fn as-is if both are
+// zero. It never reuses an existing *namedProvider (unlike the public
+// Named, which mutates one in place if fn is already wrapped), so it's safe
+// to call with a name computed from something other than fn itself, such as
+// parseBoundOrGroupedProvider's internal group-uniqueness name.
+func wrapNamed(fn any, name string, usesName map[int]string) any {
+	if name == "" && usesName == nil {
+		return fn
+	}
+	return &namedProvider{fn: fn, name: name, usesName: usesName}
+}
+
+// parseBoundOrGroupedProvider handles the two provider wrappers that can't
+// be registered by parseOneProvider alone because they need to do something
+// with the depKey it hands back: Bind additionally aliases the concrete
+// type to an interface, and Group records the depKey under a group name
+// instead of letting it claim the unnamed/default slot. ok reports whether
+// provider was one of these wrappers at all.
+//
+// Named/Uses may be applied on either side of Bind/Group (e.g. both
+// Named("x", di.Bind[Iface](fn)) and di.Bind[Iface](di.Named("x", fn)) are
+// accepted), so a wrapping *namedProvider is unwrapped wherever it's found -
+// outside the Bind/Group wrapper or inside it - and its name/usesName
+// carried through to the fn that's finally registered.
+func parseBoundOrGroupedProvider(i int, provider any, scope string, parsed map[depKey]providerInfo, aliases map[depKey]depKey, groups map[groupKey][]depKey) (ok bool, err error) {
+	name, usesName, provider := unwrapNamed(provider)
+
+	switch p := provider.(type) {
+	case *bindProvider:
+		innerName, innerUses, fn := unwrapNamed(p.fn)
+		if name == "" {
+			name = innerName
+		}
+		if usesName == nil {
+			usesName = innerUses
+		}
+		out, err := parseOneProvider(i, wrapNamed(fn, name, usesName), scope, parsed)
+		if err != nil {
+			return true, err
+		}
+		ifaceKey := depKey{typ: p.iface, name: out.name, scope: scope}
+		if duplicate, ok := aliases[ifaceKey]; ok {
+			return true, fmt.Errorf("%dth provider %s binds to %s, but provider %s already does", i, parsed[out].providerName, ifaceKey, parsed[duplicate].providerName)
+		}
+		if duplicate, ok := parsed[ifaceKey]; ok {
+			return true, fmt.Errorf("%dth provider %s binds to %s, but provider %s already does", i, parsed[out].providerName, ifaceKey, duplicate.providerName)
+		}
+		aliases[ifaceKey] = out
+		return true, nil
+	case *groupProvider:
+		innerName, innerUses, fn := unwrapNamed(p.fn)
+		if name == "" {
+			name = innerName
+		}
+		if usesName == nil {
+			usesName = innerUses
+		}
+		if name == "" {
+			name = fmt.Sprintf("__group:%s#%d", p.group, i)
+		}
+		out, err := parseOneProvider(i, wrapNamed(fn, name, usesName), scope, parsed)
+		if err != nil {
+			return true, err
+		}
+		key := groupKey{scope: scope, name: p.group}
+		groups[key] = append(groups[key], out)
+		return true, nil
+	default:
+		return false, nil
+	}
 }
 
 func getFunctionName(fn any) (string, error) {
@@ -173,10 +760,23 @@ func getFunctionName(fn any) (string, error) {
 	return fullName[lastDot+1:], nil
 }
 
-func allSubDepsProvided(allProvidedTypes map[reflect.Type]providerInfo) error {
+// canonicalDep resolves a dep through the alias table, so that imported or
+// exported module types are checked against the provider that actually
+// produces them rather than the name they were asked for under.
+func canonicalDep(key depKey, aliases map[depKey]depKey) depKey {
+	if canonical, ok := aliases[key]; ok {
+		return canonical
+	}
+	return key
+}
+
+func allSubDepsProvided(allProvidedTypes map[depKey]providerInfo, aliases map[depKey]depKey) error {
 	for _, provider := range allProvidedTypes {
-		for _, dep := range provider.deps {
-			if _, ok := allProvidedTypes[dep]; !ok {
+		for i, dep := range provider.deps {
+			if i == provider.lifecycleParam || provider.optionalDeps[i] {
+				continue
+			}
+			if _, ok := allProvidedTypes[canonicalDep(dep, aliases)]; !ok {
 				return fmt.Errorf("dependency %s is not provided", dep)
 			}
 		}
@@ -184,23 +784,173 @@ func allSubDepsProvided(allProvidedTypes map[reflect.Type]providerInfo) error {
 	return nil
 }
 
-func checkForCyclicDependencies(allProvidedTypes map[reflect.Type]providerInfo) error {
-	for providerType, provider := range allProvidedTypes {
-		providerDeps := provider.deps
-		for otherProviderType, otherProvider := range allProvidedTypes {
-			if providerType == otherProviderType {
+// dependencyGraph builds an adjacency list over allProvidedTypes, with an
+// edge from a provider's own depKey to each of its (canonicalized)
+// dependencies. A Lifecycle parameter isn't a real dependency, so it never
+// becomes an edge; an Optional[T] dependency only becomes one if a provider
+// for T actually exists, so an absent optional dependency can't introduce a
+// phantom node into the graph.
+func dependencyGraph(allProvidedTypes map[depKey]providerInfo, aliases map[depKey]depKey) map[depKey][]depKey {
+	graph := make(map[depKey][]depKey, len(allProvidedTypes))
+	for key, provider := range allProvidedTypes {
+		deps := make([]depKey, 0, len(provider.deps))
+		for i, dep := range provider.deps {
+			if i == provider.lifecycleParam {
 				continue
 			}
-			otherDepsContainsProvider := slices.Contains(otherProvider.deps, providerType)
-			providerDepsContainsOther := slices.Contains(providerDeps, otherProviderType)
-			if otherDepsContainsProvider && providerDepsContainsOther {
-				return fmt.Errorf("cyclic dependency found between providers %s and %s", provider.providerName, otherProvider.providerName)
+			dep = canonicalDep(dep, aliases)
+			if provider.optionalDeps[i] {
+				if _, ok := allProvidedTypes[dep]; !ok {
+					continue
+				}
 			}
+			deps = append(deps, dep)
+		}
+		graph[key] = deps
+	}
+	return graph
+}
+
+// checkForCyclicDependencies runs Tarjan's strongly connected components
+// algorithm over the dependency graph. A pairwise comparison only catches
+// 2-node cycles (A<->B); a chain like A->B->C->A needs the full graph. Any
+// SCC of size greater than one, or any self-edge, is a cycle.
+func checkForCyclicDependencies(allProvidedTypes map[depKey]providerInfo, graph map[depKey][]depKey) error {
+	for _, scc := range tarjanSCCs(graph) {
+		if len(scc) > 1 || slices.Contains(graph[scc[0]], scc[0]) {
+			return fmt.Errorf("cyclic dependency found: %s", describeCycle(scc, graph, allProvidedTypes))
 		}
 	}
 	return nil
 }
 
+// tarjanSCCs returns the graph's strongly connected components.
+func tarjanSCCs(graph map[depKey][]depKey) [][]depKey {
+	t := &tarjanState{
+		index:   make(map[depKey]int, len(graph)),
+		lowlink: make(map[depKey]int, len(graph)),
+		onStack: make(map[depKey]bool, len(graph)),
+	}
+	for node := range graph {
+		if _, visited := t.index[node]; !visited {
+			t.strongConnect(node, graph)
+		}
+	}
+	return t.sccs
+}
+
+type tarjanState struct {
+	index   map[depKey]int
+	lowlink map[depKey]int
+	onStack map[depKey]bool
+	stack   []depKey
+	next    int
+	sccs    [][]depKey
+}
+
+func (t *tarjanState) strongConnect(v depKey, graph map[depKey][]depKey) {
+	t.index[v] = t.next
+	t.lowlink[v] = t.next
+	t.next++
+	t.stack = append(t.stack, v)
+	t.onStack[v] = true
+
+	for _, w := range graph[v] {
+		if _, visited := t.index[w]; !visited {
+			t.strongConnect(w, graph)
+			t.lowlink[v] = min(t.lowlink[v], t.lowlink[w])
+		} else if t.onStack[w] {
+			t.lowlink[v] = min(t.lowlink[v], t.index[w])
+		}
+	}
+
+	if t.lowlink[v] != t.index[v] {
+		return
+	}
+
+	var scc []depKey
+	for {
+		n := len(t.stack) - 1
+		w := t.stack[n]
+		t.stack = t.stack[:n]
+		t.onStack[w] = false
+		scc = append(scc, w)
+		if w == v {
+			break
+		}
+	}
+	t.sccs = append(t.sccs, scc)
+}
+
+// describeCycle renders one concrete cycle within scc as a readable path,
+// e.g. "A -> B -> C -> A", using each provider's name. Every node in an SCC
+// is mutually reachable, so a plain DFS restricted to the SCC's own nodes is
+// guaranteed to find its way back to the start.
+func describeCycle(scc []depKey, graph map[depKey][]depKey, allProvidedTypes map[depKey]providerInfo) string {
+	inSCC := make(map[depKey]bool, len(scc))
+	for _, node := range scc {
+		inSCC[node] = true
+	}
+
+	start := scc[0]
+	path := []depKey{start}
+	visited := map[depKey]bool{start: true}
+
+	var dfs func(depKey) bool
+	dfs = func(node depKey) bool {
+		for _, next := range graph[node] {
+			if !inSCC[next] {
+				continue
+			}
+			if next == start {
+				path = append(path, start)
+				return true
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			path = append(path, next)
+			if dfs(next) {
+				return true
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+	dfs(start)
+
+	names := make([]string, len(path))
+	for i, node := range path {
+		names[i] = allProvidedTypes[node].providerName
+	}
+	return strings.Join(names, " -> ")
+}
+
+// topologicalOrder returns every canonical depKey in allProvidedTypes such
+// that a provider always appears after everything it depends on. Callers
+// must first ensure the graph is acyclic.
+func topologicalOrder(allProvidedTypes map[depKey]providerInfo, graph map[depKey][]depKey) []depKey {
+	visited := make(map[depKey]bool, len(allProvidedTypes))
+	order := make([]depKey, 0, len(allProvidedTypes))
+
+	var visit func(depKey)
+	visit = func(node depKey) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		for _, dep := range graph[node] {
+			visit(dep)
+		}
+		order = append(order, node)
+	}
+	for node := range allProvidedTypes {
+		visit(node)
+	}
+	return order
+}
+
 func (c *Provider) Provide(dst any) error {
 	dstType := reflect.TypeOf(dst)
 	if dstType.Kind() != reflect.Ptr || dstType.Elem().Kind() != reflect.Struct {
@@ -211,15 +961,48 @@ func (c *Provider) Provide(dst any) error {
 		field := dstValue.Field(i)
 		fieldType := dstType.Elem().Field(i)
 		diTag := fieldType.Tag.Get("di")
-		switch diTag {
-		case "group":
+		switch {
+		case diTag == "group":
 			if err := c.resolveGroup(field, fieldType); err != nil {
 				return fmt.Errorf("failed to resolve group %s: %w", fieldType.Name, err)
 			}
-		case "-":
+		case strings.HasPrefix(diTag, "group="):
+			groupName := strings.TrimPrefix(diTag, "group=")
+			if err := c.resolveGroupField(field, groupName); err != nil {
+				return fmt.Errorf("failed to resolve group %s: %w", fieldType.Name, err)
+			}
+		case diTag == "-":
 			continue
+		case strings.HasPrefix(diTag, "name="):
+			name := strings.TrimPrefix(diTag, "name=")
+			fieldValue, err := c.resolve(depKey{typ: fieldType.Type, name: name})
+			if err != nil {
+				return fmt.Errorf("failed to resolve field %s: %w", fieldType.Name, err)
+			}
+			field.Set(fieldValue)
+		case diTag == "optional":
+			if !c.hasProvider(depKey{typ: fieldType.Type}) {
+				continue
+			}
+			fieldValue, err := c.resolve(depKey{typ: fieldType.Type})
+			if err != nil {
+				return fmt.Errorf("failed to resolve field %s: %w", fieldType.Name, err)
+			}
+			field.Set(fieldValue)
+		case strings.HasPrefix(diTag, "default="):
+			if !c.hasProvider(depKey{typ: fieldType.Type}) {
+				if err := setDefaultValue(field, strings.TrimPrefix(diTag, "default=")); err != nil {
+					return fmt.Errorf("failed to set default for field %s: %w", fieldType.Name, err)
+				}
+				continue
+			}
+			fieldValue, err := c.resolve(depKey{typ: fieldType.Type})
+			if err != nil {
+				return fmt.Errorf("failed to resolve field %s: %w", fieldType.Name, err)
+			}
+			field.Set(fieldValue)
 		default:
-			fieldValue, err := c.resolve(fieldType.Type)
+			fieldValue, err := c.resolve(depKey{typ: fieldType.Type})
 			if err != nil {
 				return fmt.Errorf("failed to resolve field %s: %w", fieldType.Name, err)
 			}
@@ -230,35 +1013,276 @@ func (c *Provider) Provide(dst any) error {
 	return nil
 }
 
-func (c *Provider) resolve(fieldType reflect.Type) (reflect.Value, error) {
-	if value, ok := c.resolvedTypes[fieldType]; ok {
-		return value, nil
+// ProvideModule fills dst like Provide, but only resolves fields against the
+// types exported by the named module, rather than the whole dependency
+// graph. This lets callers inject a narrow, module-scoped view even if the
+// same type happens to be available elsewhere in the graph.
+func (c *Provider) ProvideModule(name string, dst any) error {
+	exports, ok := c.modules[name]
+	if !ok {
+		return fmt.Errorf("no module named %q", name)
 	}
 
-	provider, ok := c.allProvidedTypes[fieldType]
-	if !ok {
-		return reflect.Value{}, fmt.Errorf("no provider found for type %s", fieldType)
+	dstType := reflect.TypeOf(dst)
+	if dstType.Kind() != reflect.Ptr || dstType.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be a pointer to a struct, got %s", dstType.Kind())
 	}
+	dstValue := reflect.ValueOf(dst).Elem()
+	for i := 0; i < dstValue.NumField(); i++ {
+		field := dstValue.Field(i)
+		fieldType := dstType.Elem().Field(i)
+		diTag := fieldType.Tag.Get("di")
+		if diTag == "-" {
+			continue
+		}
+		fieldName := ""
+		if strings.HasPrefix(diTag, "name=") {
+			fieldName = strings.TrimPrefix(diTag, "name=")
+		}
+
+		key := depKey{typ: fieldType.Type, name: fieldName}
+		if _, ok := exports[key]; !ok {
+			return fmt.Errorf("failed to resolve field %s: type %s is not exported by module %q", fieldType.Name, key, name)
+		}
+		fieldValue, err := c.resolve(key)
+		if err != nil {
+			return fmt.Errorf("failed to resolve field %s: %w", fieldType.Name, err)
+		}
+		field.Set(fieldValue)
+	}
+
+	return nil
+}
 
-	resolvedDeps := make([]reflect.Value, 0, len(provider.deps))
-	for _, depType := range provider.deps {
-		depValue, err := c.resolve(depType)
+// hasProvider reports whether key, after alias indirection, has a
+// registered provider. Used by di:"optional" and di:"default=..." fields to
+// tell "nothing provides this" apart from "the provider failed".
+func (c *Provider) hasProvider(key depKey) bool {
+	_, ok := c.allProvidedTypes[canonicalDep(key, c.aliases)]
+	return ok
+}
+
+// setDefaultValue parses expr as a scalar literal matching field's kind and
+// assigns it, for a di:"default=<expr>" field whose provider is absent.
+func setDefaultValue(field reflect.Value, expr string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(expr)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(expr)
+		if err != nil {
+			return fmt.Errorf("invalid default %q for bool field: %w", expr, err)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(expr, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid default %q for int field: %w", expr, err)
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(expr, 64)
 		if err != nil {
-			return reflect.Value{}, fmt.Errorf("failed to resolve dependency %s: %w", depType, err)
+			return fmt.Errorf("invalid default %q for float field: %w", expr, err)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("di:\"default=...\" is not supported for field kind %s", field.Kind())
+	}
+	return nil
+}
+
+// resolve returns the value for key, constructing it and anything it
+// transitively depends on that hasn't been built yet. It walks the
+// provider's cached topoOrder instead of recursing through deps itself, so
+// every dependency is built before its dependents and a single pass handles
+// arbitrarily deep chains. topoOrder is built by ranging over a map, so the
+// relative order of providers that don't depend on each other is not fixed
+// and can differ from one Provider to the next.
+func (c *Provider) resolve(key depKey) (reflect.Value, error) {
+	key = canonicalDep(key, c.aliases)
+
+	if value, ok := c.resolvedTypes[key]; ok {
+		return value, nil
+	}
+
+	if _, ok := c.allProvidedTypes[key]; !ok {
+		return reflect.Value{}, fmt.Errorf("no provider found for type %s", key)
+	}
+
+	needed := c.neededFor(key)
+	for _, candidate := range c.topoOrder {
+		if _, ok := needed[candidate]; !ok {
+			continue
+		}
+		if _, ok := c.resolvedTypes[candidate]; ok {
+			continue
+		}
+
+		provider := c.allProvidedTypes[candidate]
+		resolvedDeps := make([]reflect.Value, 0, len(provider.deps))
+		for i, dep := range provider.deps {
+			if i == provider.lifecycleParam {
+				resolvedDeps = append(resolvedDeps, reflect.ValueOf(&lifecycleRecorder{provider: c, key: candidate}))
+				continue
+			}
+			dep = canonicalDep(dep, c.aliases)
+			depValue, ok := c.resolvedTypes[dep]
+			if provider.optionalDeps[i] {
+				resolvedDeps = append(resolvedDeps, optionalValue(provider.provider.Type().In(i), depValue, ok))
+				continue
+			}
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("failed to resolve dependency %s for %s: not constructed in topological order", dep, provider.providerName)
+			}
+			resolvedDeps = append(resolvedDeps, depValue)
+		}
+
+		results := provider.provider.Call(resolvedDeps)
+		if len(results) == 2 && results[1].Interface() != nil {
+			resolutionError := results[1].Interface().(error)
+			return reflect.Value{}, fmt.Errorf("%s failed to resolve value: %w", provider.providerName, resolutionError)
+		}
+		c.resolvedTypes[candidate] = results[0]
+		c.autoDetectHooks(candidate, results[0])
+	}
+
+	return c.resolvedTypes[key], nil
+}
+
+// autoDetectHooks registers an implicit lifecycle hook for a freshly
+// constructed value that implements it, so callers don't have to take a
+// Lifecycle parameter just to close a resource. A Start(ctx)/Stop(ctx) pair
+// takes precedence over io.Closer.
+func (c *Provider) autoDetectHooks(key depKey, value reflect.Value) {
+	if !value.CanInterface() {
+		return
+	}
+	switch v := value.Interface().(type) {
+	case starterStopper:
+		c.hooks = append(c.hooks, registeredHook{key: key, hook: starterStopperHook{v}})
+	case io.Closer:
+		c.hooks = append(c.hooks, registeredHook{key: key, hook: closerHook{v}})
+	}
+}
+
+// Start runs every registered lifecycle hook's OnStart, in the order of its
+// provider's position in topoOrder (so a hook never starts before something
+// it depends on), stopping at the first failure.
+func (c *Provider) Start(ctx context.Context) error {
+	for _, h := range c.orderedHooks() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := runHook(ctx, h.hook, h.hook.OnStart); err != nil {
+			return fmt.Errorf("failed to start %s: %w", h.key, err)
 		}
-		resolvedDeps = append(resolvedDeps, depValue)
 	}
+	return nil
+}
 
-	results := provider.provider.Call(resolvedDeps)
-	if len(results) == 2 && results[1].Interface() != nil {
-		resolutionError := results[1].Interface().(error)
-		return reflect.Value{}, fmt.Errorf("%s failed to resolve value: %w", provider.providerName, resolutionError)
+// Stop runs every registered lifecycle hook's OnStop in reverse topoOrder,
+// so a hook is always stopped before the things it depends on. Unlike
+// Start, Stop runs every hook even if one of them fails, aggregating their
+// errors with errors.Join. It still honors ctx the same way Start does,
+// though: once ctx is canceled, the hooks that haven't run yet are given up
+// on rather than attempted, since there's no reason to expect them to
+// succeed where ctx itself has already run out.
+func (c *Provider) Stop(ctx context.Context) error {
+	hooks := c.orderedHooks()
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := runHook(ctx, hooks[i].hook, hooks[i].hook.OnStop); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hooks[i].key, err))
+		}
 	}
-	resolvedValue := results[0]
+	return errors.Join(errs...)
+}
 
-	c.resolvedTypes[fieldType] = resolvedValue
+// runHook calls fn (a hook's OnStart or OnStop) under ctx, narrowing ctx to
+// the hook's own HookTimeout first if it declares one.
+func runHook(ctx context.Context, hook Hook, fn func(context.Context) error) error {
+	if ht, ok := hook.(HookTimeout); ok {
+		if d := ht.Timeout(); d > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+	}
+	return fn(ctx)
+}
 
-	return resolvedValue, nil
+// orderedHooks returns the registered hooks sorted by their provider's
+// position in topoOrder.
+func (c *Provider) orderedHooks() []registeredHook {
+	sorted := make([]registeredHook, len(c.hooks))
+	copy(sorted, c.hooks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return c.topoIndex[sorted[i].key] < c.topoIndex[sorted[j].key]
+	})
+	return sorted
+}
+
+// neededFor collects key and every canonical depKey it transitively depends
+// on, walking the graph with an explicit stack rather than recursion. A
+// Lifecycle parameter isn't a real dependency, so it's skipped.
+func (c *Provider) neededFor(key depKey) map[depKey]struct{} {
+	needed := map[depKey]struct{}{key: {}}
+	stack := []depKey{key}
+	for len(stack) > 0 {
+		n := len(stack) - 1
+		current := stack[n]
+		stack = stack[:n]
+
+		provider := c.allProvidedTypes[current]
+		for i, dep := range provider.deps {
+			if i == provider.lifecycleParam {
+				continue
+			}
+			dep = canonicalDep(dep, c.aliases)
+			if provider.optionalDeps[i] {
+				if _, ok := c.allProvidedTypes[dep]; !ok {
+					continue
+				}
+			}
+			if _, ok := needed[dep]; ok {
+				continue
+			}
+			needed[dep] = struct{}{}
+			stack = append(stack, dep)
+		}
+	}
+	return needed
+}
+
+// resolveGroupField fills a slice field with one constructed value from
+// every provider registered under the named group via Group, in
+// registration order. Only groups registered at the top level (outside any
+// Module) are visible here, mirroring how an un-exported module type can't
+// be resolved from outside the module either.
+func (c *Provider) resolveGroupField(field reflect.Value, group string) error {
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf("`di:\"group=...\"` can only be used on slice fields, got %s", field.Kind())
+	}
+
+	keys := c.groups[groupKey{name: group}]
+	slice := reflect.MakeSlice(field.Type(), 0, len(keys))
+	for _, key := range keys {
+		value, err := c.resolve(key)
+		if err != nil {
+			return fmt.Errorf("group %q: %w", group, err)
+		}
+		if !value.Type().AssignableTo(field.Type().Elem()) {
+			return fmt.Errorf("group %q: value of type %s is not assignable to %s", group, value.Type(), field.Type().Elem())
+		}
+		slice = reflect.Append(slice, value)
+	}
+	field.Set(slice)
+	return nil
 }
 
 func (c *Provider) resolveGroup(field reflect.Value, fieldType reflect.StructField) error {
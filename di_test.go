@@ -1,9 +1,12 @@
 package di
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewProvider(t *testing.T) {
@@ -54,6 +57,40 @@ func TestNewProvider(t *testing.T) {
 		requireError(t, err)
 		requireContains(t, err.Error(), "should not have cyclic dependencies")
 	})
+	t.Run("3-cycle dependency", func(t *testing.T) {
+		type A struct{}
+		type B struct{}
+		type C struct{}
+		_, err := NewProvider(
+			func(B) A { return A{} },
+			func(C) B { return B{} },
+			func(A) C { return C{} },
+		)
+		requireError(t, err)
+		requireContains(t, err.Error(), "should not have cyclic dependencies")
+	})
+	t.Run("4-cycle dependency", func(t *testing.T) {
+		type A struct{}
+		type B struct{}
+		type C struct{}
+		type D struct{}
+		_, err := NewProvider(
+			func(B) A { return A{} },
+			func(C) B { return B{} },
+			func(D) C { return C{} },
+			func(A) D { return D{} },
+		)
+		requireError(t, err)
+		requireContains(t, err.Error(), "should not have cyclic dependencies")
+	})
+	t.Run("self dependency", func(t *testing.T) {
+		type A struct{}
+		_, err := NewProvider(
+			func(A) A { return A{} },
+		)
+		requireError(t, err)
+		requireContains(t, err.Error(), "should not have cyclic dependencies")
+	})
 	t.Run("valid providers", func(t *testing.T) {
 		providerFunc1 := func() string { return "hello" }
 		providerFunc2 := func(s string) int { return len(s) }
@@ -255,8 +292,685 @@ func TestProvider_Provide(t *testing.T) {
 
 		requireEqual(t, "", dst.Ignored)
 	})
+
+	t.Run("named providers", func(t *testing.T) {
+		type DB struct {
+			DSN string
+		}
+
+		provider, err := NewProvider(
+			Named("primary", func() *DB { return &DB{DSN: "primary-dsn"} }),
+			Named("replica", func() *DB { return &DB{DSN: "replica-dsn"} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Primary *DB `di:"name=primary"`
+			Replica *DB `di:"name=replica"`
+		}{}
+
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		requireEqual(t, "primary-dsn", dst.Primary.DSN)
+		requireEqual(t, "replica-dsn", dst.Replica.DSN)
+	})
+
+	t.Run("named provider dependency via Uses", func(t *testing.T) {
+		type DB struct {
+			DSN string
+		}
+		type Repo struct {
+			DB *DB
+		}
+
+		provider, err := NewProvider(
+			Named("primary", func() *DB { return &DB{DSN: "primary-dsn"} }),
+			Named("replica", func() *DB { return &DB{DSN: "replica-dsn"} }),
+			Uses(0, "replica", func(db *DB) *Repo { return &Repo{DB: db} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Repo *Repo
+		}{}
+
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		requireEqual(t, "replica-dsn", dst.Repo.DB.DSN)
+	})
+
+	t.Run("missing named binding", func(t *testing.T) {
+		type DB struct{}
+
+		provider, err := NewProvider(
+			Named("primary", func() *DB { return &DB{} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Replica *DB `di:"name=replica"`
+		}{}
+
+		err = provider.Provide(dst)
+		requireError(t, err)
+		requireContains(t, err.Error(), "no provider found for type")
+	})
+
+	t.Run("module exports are visible, internals are not", func(t *testing.T) {
+		type Config struct {
+			Secret string
+		}
+		type TokenIssuer struct {
+			Config *Config
+		}
+
+		authModule := NewModule("auth",
+			func() *Config { return &Config{Secret: "shh"} },
+			func(cfg *Config) *TokenIssuer { return &TokenIssuer{Config: cfg} },
+		).Export((*TokenIssuer)(nil))
+
+		provider, err := NewProvider(authModule)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Issuer *TokenIssuer
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, "shh", dst.Issuer.Config.Secret)
+
+		// Config was not exported, so it must not leak into the top-level graph.
+		cfgDst := &struct {
+			Cfg *Config
+		}{}
+		err = provider.Provide(cfgDst)
+		requireError(t, err)
+	})
+
+	t.Run("two modules privately providing the same type", func(t *testing.T) {
+		type Config struct {
+			Owner string
+		}
+		type AuthService struct {
+			Config *Config
+		}
+		type Billing struct {
+			Config *Config
+		}
+
+		authModule := NewModule("auth",
+			func() *Config { return &Config{Owner: "auth"} },
+			func(cfg *Config) *AuthService { return &AuthService{Config: cfg} },
+		).Export((*AuthService)(nil))
+
+		billingModule := NewModule("billing",
+			func() *Config { return &Config{Owner: "billing"} },
+			func(cfg *Config) *Billing { return &Billing{Config: cfg} },
+		).Export((*Billing)(nil))
+
+		provider, err := NewProvider(authModule, billingModule)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Auth    *AuthService
+			Billing *Billing
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, "auth", dst.Auth.Config.Owner)
+		requireEqual(t, "billing", dst.Billing.Config.Owner)
+	})
+
+	t.Run("module using another module's export", func(t *testing.T) {
+		type Config struct{}
+		type TokenIssuer struct{}
+		type Billing struct {
+			Issuer *TokenIssuer
+		}
+
+		authModule := NewModule("auth",
+			func() *Config { return &Config{} },
+			func(*Config) *TokenIssuer { return &TokenIssuer{} },
+		).Export((*TokenIssuer)(nil))
+
+		billingModule := NewModule("billing",
+			func(issuer *TokenIssuer) *Billing { return &Billing{Issuer: issuer} },
+		).Use(authModule).Export((*Billing)(nil))
+
+		provider, err := NewProvider(billingModule)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Billing *Billing
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+	})
+
+	t.Run("ProvideModule scopes injection to one module's exports", func(t *testing.T) {
+		type Config struct{}
+		type Billing struct{}
+
+		billingModule := NewModule("billing",
+			func() *Config { return &Config{} },
+			func(*Config) *Billing { return &Billing{} },
+		).Export((*Billing)(nil))
+
+		provider, err := NewProvider(billingModule)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Billing *Billing
+		}{}
+		err = provider.ProvideModule("billing", dst)
+		requireNoError(t, err)
+
+		cfgDst := &struct {
+			Cfg *Config
+		}{}
+		err = provider.ProvideModule("billing", cfgDst)
+		requireError(t, err)
+		requireContains(t, err.Error(), "not exported by module")
+	})
+
+	t.Run("export of an unprovided type is an error", func(t *testing.T) {
+		type Unprovided struct{}
+
+		m := NewModule("broken").Export((*Unprovided)(nil))
+		_, err := NewProvider(m)
+		requireError(t, err)
+		requireContains(t, err.Error(), "cannot export type")
+	})
+
+	t.Run("Bind lets a dependent ask for the interface", func(t *testing.T) {
+		provider, err := NewProvider(
+			Bind[storage](func() *s3Storage { return &s3Storage{} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Storage storage
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		_, ok := dst.Storage.(*s3Storage)
+		requireTrue(t, ok)
+	})
+
+	t.Run("Bind still registers the concrete type", func(t *testing.T) {
+		provider, err := NewProvider(
+			Bind[storage](func() *s3Storage { return &s3Storage{} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Storage *s3Storage
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+	})
+
+	t.Run("Named and Bind compose regardless of wrapping order", func(t *testing.T) {
+		outer, err := NewProvider(
+			Named("blob", Bind[storage](func() *s3Storage { return &s3Storage{} })),
+		)
+		requireNoError(t, err)
+		inner, err := NewProvider(
+			Bind[storage](Named("blob", func() *s3Storage { return &s3Storage{} })),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Storage storage `di:"name=blob"`
+		}{}
+		for _, provider := range []*Provider{outer, inner} {
+			dst.Storage = nil
+			err = provider.Provide(dst)
+			requireNoError(t, err)
+			_, ok := dst.Storage.(*s3Storage)
+			requireTrue(t, ok)
+		}
+	})
+
+	t.Run("Named and Group compose regardless of wrapping order", func(t *testing.T) {
+		outer, err := NewProvider(
+			Named("ping", Group("handlers", func() handler { return pingHandler{} })),
+		)
+		requireNoError(t, err)
+		inner, err := NewProvider(
+			Group("handlers", Named("ping", func() handler { return pingHandler{} })),
+		)
+		requireNoError(t, err)
+
+		for _, provider := range []*Provider{outer, inner} {
+			dst := &struct {
+				Named    handler   `di:"name=ping"`
+				Handlers []handler `di:"group=handlers"`
+			}{}
+			err = provider.Provide(dst)
+			requireNoError(t, err)
+			_, ok := dst.Named.(pingHandler)
+			requireTrue(t, ok)
+			requireEqual(t, 1, len(dst.Handlers))
+		}
+	})
+
+	t.Run("Group collects every provider into a slice field", func(t *testing.T) {
+		provider, err := NewProvider(
+			Group("handlers", func() handler { return pingHandler{} }),
+			Group("handlers", func() handler { return healthHandler{} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Handlers []handler `di:"group=handlers"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, 2, len(dst.Handlers))
+	})
+
+	t.Run("Group members may be concrete types assignable to the field's element type", func(t *testing.T) {
+		provider, err := NewProvider(
+			Group("handlers", func() *pingHandler { return &pingHandler{} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Handlers []*pingHandler `di:"group=handlers"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, 1, len(dst.Handlers))
+	})
+
+	t.Run("group= on a non-slice field is an error", func(t *testing.T) {
+		provider, err := NewProvider(
+			Group("handlers", func() string { return "hello" }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Handlers string `di:"group=handlers"`
+		}{}
+		err = provider.Provide(dst)
+		requireError(t, err)
+		requireContains(t, err.Error(), "can only be used on slice fields")
+	})
+
+	t.Run("a module's group is private and not visible from outside it", func(t *testing.T) {
+		m := NewModule("internal",
+			Group("handlers", func() handler { return pingHandler{} }),
+		)
+		provider, err := NewProvider(
+			m,
+			Group("handlers", func() handler { return healthHandler{} }),
+		)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Handlers []handler `di:"group=handlers"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, 1, len(dst.Handlers))
+		_, ok := dst.Handlers[0].(healthHandler)
+		requireTrue(t, ok)
+	})
+
+	t.Run("two modules may each use the same group name without colliding", func(t *testing.T) {
+		a := NewModule("a", Group("handlers", func() handler { return pingHandler{} }))
+		b := NewModule("b", Group("handlers", func() handler { return healthHandler{} }))
+		provider, err := NewProvider(a, b)
+		requireNoError(t, err)
+
+		dst := &struct {
+			Handlers []handler `di:"group=handlers"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, 0, len(dst.Handlers))
+	})
 }
 
+func TestProvider_Lifecycle(t *testing.T) {
+	t.Run("hooks start in construction order and stop in reverse", func(t *testing.T) {
+		type DB struct{}
+		type Server struct{ DB *DB }
+
+		var log []string
+
+		dbProvider := func(lc Lifecycle) *DB {
+			lc.Append(funcHook{
+				onStart: func(context.Context) error { log = append(log, "db started"); return nil },
+				onStop:  func(context.Context) error { log = append(log, "db stopped"); return nil },
+			})
+			return &DB{}
+		}
+		serverProvider := func(db *DB, lc Lifecycle) *Server {
+			lc.Append(funcHook{
+				onStart: func(context.Context) error { log = append(log, "server started"); return nil },
+				onStop:  func(context.Context) error { log = append(log, "server stopped"); return nil },
+			})
+			return &Server{DB: db}
+		}
+
+		provider, err := NewProvider(dbProvider, serverProvider)
+		requireNoError(t, err)
+
+		dst := &struct{ Server *Server }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		requireNoError(t, provider.Start(context.Background()))
+		requireNoError(t, provider.Stop(context.Background()))
+
+		requireEqual(t, "[db started server started server stopped db stopped]", fmt.Sprintf("%v", log))
+	})
+
+	t.Run("Start stops at the first failing hook", func(t *testing.T) {
+		type A struct{}
+		type B struct{}
+		startErr := errors.New("boom")
+
+		var bStarted bool
+		providerA := func(lc Lifecycle) *A {
+			lc.Append(funcHook{
+				onStart: func(context.Context) error { return startErr },
+				onStop:  func(context.Context) error { return nil },
+			})
+			return &A{}
+		}
+		providerB := func(a *A, lc Lifecycle) *B {
+			lc.Append(funcHook{
+				onStart: func(context.Context) error { bStarted = true; return nil },
+				onStop:  func(context.Context) error { return nil },
+			})
+			return &B{}
+		}
+
+		provider, err := NewProvider(providerA, providerB)
+		requireNoError(t, err)
+
+		dst := &struct{ B *B }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		err = provider.Start(context.Background())
+		requireErrorIs(t, err, startErr)
+		requireFalse(t, bStarted)
+	})
+
+	t.Run("Stop runs every hook even if one fails, aggregating errors", func(t *testing.T) {
+		type A struct{}
+		type B struct{}
+		stopErrB := errors.New("b failed to stop")
+
+		var aStopped bool
+		providerA := func(lc Lifecycle) *A {
+			lc.Append(funcHook{
+				onStart: func(context.Context) error { return nil },
+				onStop: func(context.Context) error {
+					aStopped = true
+					return nil
+				},
+			})
+			return &A{}
+		}
+		providerB := func(a *A, lc Lifecycle) *B {
+			lc.Append(funcHook{
+				onStart: func(context.Context) error { return nil },
+				onStop:  func(context.Context) error { return stopErrB },
+			})
+			return &B{}
+		}
+
+		provider, err := NewProvider(providerA, providerB)
+		requireNoError(t, err)
+
+		dst := &struct{ B *B }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		requireNoError(t, provider.Start(context.Background()))
+		err = provider.Stop(context.Background())
+		requireErrorIs(t, err, stopErrB)
+		requireTrue(t, aStopped)
+	})
+
+	t.Run("io.Closer is auto-registered and closed on Stop", func(t *testing.T) {
+		closed := false
+
+		provider, err := NewProvider(func() fakeCloser { return fakeCloser{closed: &closed} })
+		requireNoError(t, err)
+
+		dst := &struct{ C fakeCloser }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		requireNoError(t, provider.Start(context.Background()))
+		requireNoError(t, provider.Stop(context.Background()))
+		requireTrue(t, closed)
+	})
+
+	t.Run("a hook that respects ctx is cut off by its own HookTimeout on Stop", func(t *testing.T) {
+		type A struct{}
+
+		providerA := func(lc Lifecycle) *A {
+			lc.Append(funcHook{
+				onStart: func(context.Context) error { return nil },
+				onStop: func(ctx context.Context) error {
+					select {
+					case <-time.After(time.Minute):
+						return nil
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				},
+				timeout: 10 * time.Millisecond,
+			})
+			return &A{}
+		}
+
+		provider, err := NewProvider(providerA)
+		requireNoError(t, err)
+
+		dst := &struct{ A *A }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		requireNoError(t, provider.Start(context.Background()))
+
+		done := make(chan error, 1)
+		go func() { done <- provider.Stop(context.Background()) }()
+
+		select {
+		case err := <-done:
+			requireErrorIs(t, err, context.DeadlineExceeded)
+		case <-time.After(time.Second):
+			t.Fatal("Stop did not return within its hook's timeout")
+		}
+	})
+
+	t.Run("Start/Stop pair is auto-registered", func(t *testing.T) {
+		var log []string
+
+		provider, err := NewProvider(func() fakeService { return fakeService{log: &log} })
+		requireNoError(t, err)
+
+		dst := &struct{ S fakeService }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+
+		requireNoError(t, provider.Start(context.Background()))
+		requireNoError(t, provider.Stop(context.Background()))
+		requireEqual(t, "[service started service stopped]", fmt.Sprintf("%v", log))
+	})
+}
+
+func TestProvider_OptionalAndDefault(t *testing.T) {
+	t.Run("optional field is left at zero value when no provider is registered", func(t *testing.T) {
+		provider, err := NewProvider(func() int { return 42 })
+		requireNoError(t, err)
+
+		dst := &struct {
+			S string `di:"optional"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, "", dst.S)
+	})
+
+	t.Run("optional field is filled when a provider is registered", func(t *testing.T) {
+		provider, err := NewProvider(func() string { return "hello" })
+		requireNoError(t, err)
+
+		dst := &struct {
+			S string `di:"optional"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, "hello", dst.S)
+	})
+
+	t.Run("default field falls back to the tag's literal when no provider is registered", func(t *testing.T) {
+		provider, err := NewProvider()
+		requireNoError(t, err)
+
+		dst := &struct {
+			Timeout int `di:"default=30"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, 30, dst.Timeout)
+	})
+
+	t.Run("default field uses the provider when one is registered", func(t *testing.T) {
+		type Timeout int
+		provider, err := NewProvider(func() Timeout { return 5 })
+		requireNoError(t, err)
+
+		dst := &struct {
+			Timeout Timeout `di:"default=30"`
+		}{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireEqual(t, Timeout(5), dst.Timeout)
+	})
+
+	t.Run("invalid default literal is an error", func(t *testing.T) {
+		provider, err := NewProvider(func() string { return "hello" })
+		requireNoError(t, err)
+
+		dst := &struct {
+			Retries int `di:"default=not-a-number"`
+		}{}
+		err = provider.Provide(dst)
+		requireError(t, err)
+		requireContains(t, err.Error(), "invalid default")
+	})
+
+	t.Run("Optional[T] provider parameter is present when the dependency is registered", func(t *testing.T) {
+		type Tracer struct {
+			Exporter string
+			Enabled  bool
+		}
+
+		provider, err := NewProvider(
+			func() string { return "otlp" },
+			func(exporter Optional[string]) *Tracer {
+				v, ok := exporter.Get()
+				return &Tracer{Exporter: v, Enabled: ok}
+			},
+		)
+		requireNoError(t, err)
+
+		dst := &struct{ Tracer *Tracer }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireTrue(t, dst.Tracer.Enabled)
+		requireEqual(t, "otlp", dst.Tracer.Exporter)
+	})
+
+	t.Run("Optional[T] provider parameter is absent when the dependency isn't registered", func(t *testing.T) {
+		type Tracer struct {
+			Exporter string
+			Enabled  bool
+		}
+
+		provider, err := NewProvider(
+			func(exporter Optional[string]) *Tracer {
+				v, ok := exporter.Get()
+				return &Tracer{Exporter: v, Enabled: ok}
+			},
+		)
+		requireNoError(t, err)
+
+		dst := &struct{ Tracer *Tracer }{}
+		err = provider.Provide(dst)
+		requireNoError(t, err)
+		requireFalse(t, dst.Tracer.Enabled)
+		requireEqual(t, "", dst.Tracer.Exporter)
+	})
+}
+
+// funcHook, fakeCloser and fakeService back the lifecycle tests above;
+// methods can't be attached to types declared inside a test function, so
+// they live here at package scope instead.
+type funcHook struct {
+	onStart func(context.Context) error
+	onStop  func(context.Context) error
+	timeout time.Duration
+}
+
+func (h funcHook) OnStart(ctx context.Context) error { return h.onStart(ctx) }
+func (h funcHook) OnStop(ctx context.Context) error  { return h.onStop(ctx) }
+func (h funcHook) Timeout() time.Duration            { return h.timeout }
+
+type fakeCloser struct{ closed *bool }
+
+func (f fakeCloser) Close() error {
+	*f.closed = true
+	return nil
+}
+
+type fakeService struct{ log *[]string }
+
+func (s fakeService) Start(context.Context) error {
+	*s.log = append(*s.log, "service started")
+	return nil
+}
+
+func (s fakeService) Stop(context.Context) error {
+	*s.log = append(*s.log, "service stopped")
+	return nil
+}
+
+// storage, s3Storage, handler, pingHandler and healthHandler back the Bind
+// and Group tests above; methods can't be attached to types declared inside
+// a test function, so they live here at package scope instead.
+type storage interface {
+	Put(string)
+}
+
+type s3Storage struct{}
+
+func (*s3Storage) Put(string) {}
+
+type handler interface {
+	Handle() string
+}
+
+type pingHandler struct{}
+
+func (pingHandler) Handle() string { return "ping" }
+
+type healthHandler struct{}
+
+func (healthHandler) Handle() string { return "health" }
+
 func requireEqual[T comparable](t *testing.T, a, b T) {
 	t.Helper()
 	if a != b {
@@ -292,6 +1006,13 @@ func requireTrue(t *testing.T, b bool) {
 	}
 }
 
+func requireFalse(t *testing.T, b bool) {
+	t.Helper()
+	if b {
+		t.Fatalf("expected false, got true")
+	}
+}
+
 func requireContains(t *testing.T, s, substr string) {
 	t.Helper()
 	if !strings.Contains(s, substr) {